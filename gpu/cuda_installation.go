@@ -0,0 +1,212 @@
+package gpu
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CudaInstallation describes one candidate CUDA toolkit found on disk, in
+// the same spirit as clang's CudaInstallationDetector: a root directory
+// plus the version declared by that root's version.txt/version.json.
+type CudaInstallation struct {
+	Root    string
+	LibDir  string
+	Version [3]int // major, minor, patch
+}
+
+func (c CudaInstallation) String() string {
+	return fmt.Sprintf("%d.%d.%d (%s)", c.Version[0], c.Version[1], c.Version[2], c.Root)
+}
+
+// CudaInstallRootGlobs are the locations clang and nvcc itself look for a
+// CUDA toolkit install. $CUDA_HOME, $CUDA_PATH and OLLAMA_CUDA_PATH are
+// consulted separately since they name an exact root rather than a glob.
+var CudaInstallRootGlobs = []string{
+	"/usr/local/cuda",
+	"/usr/local/cuda-*",
+	"/opt/cuda",
+	"/opt/cuda-*",
+	"/usr/lib/x86_64-linux-gnu/nvidia/current",
+	"/usr/lib/aarch64-linux-gnu/nvidia/current",
+}
+
+var versionTxtPattern = regexp.MustCompile(`CUDA Version (\d+)\.(\d+)\.(\d+)`)
+
+// DiscoverCudaInstallations walks the well-known CUDA toolkit locations
+// (plus OLLAMA_CUDA_PATH/CUDA_HOME/CUDA_PATH when set) and returns every
+// root whose version.txt or version.json could be parsed. Roots that exist
+// but don't carry a readable version file are skipped rather than guessed
+// at, since loading the wrong libcudart.so is exactly the failure mode
+// this is meant to avoid.
+func DiscoverCudaInstallations() []CudaInstallation {
+	var roots []string
+	if p := os.Getenv("OLLAMA_CUDA_PATH"); p != "" {
+		roots = append(roots, p)
+	}
+	if p := os.Getenv("CUDA_HOME"); p != "" {
+		roots = append(roots, p)
+	}
+	if p := os.Getenv("CUDA_PATH"); p != "" {
+		roots = append(roots, p)
+	}
+	for _, pattern := range CudaInstallRootGlobs {
+		matches, _ := filepath.Glob(pattern)
+		roots = append(roots, matches...)
+	}
+
+	seen := map[string]bool{}
+	var installs []CudaInstallation
+	for _, root := range roots {
+		abs, err := filepath.Abs(root)
+		if err != nil || seen[abs] {
+			continue
+		}
+		seen[abs] = true
+		version, err := parseCudaVersion(abs)
+		if err != nil {
+			slog.Debug(fmt.Sprintf("skipping CUDA root %s: %s", abs, err))
+			continue
+		}
+		installs = append(installs, CudaInstallation{
+			Root:    abs,
+			LibDir:  cudaLibDir(abs),
+			Version: version,
+		})
+	}
+	return installs
+}
+
+// cudaLibDir guesses the lib directory for a toolkit root, preferring the
+// 64-bit lib directory layout used by every CUDA release to date.
+func cudaLibDir(root string) string {
+	for _, candidate := range []string{"lib64", "lib/x64", "lib"} {
+		dir := filepath.Join(root, candidate)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return filepath.Join(root, "lib64")
+}
+
+// parseCudaVersion reads <root>/version.json, the layout newer toolkit
+// installers write, falling back to the older plaintext version.txt
+// ("CUDA Version X.Y.Z") used by releases prior to CUDA 11.3.
+func parseCudaVersion(root string) ([3]int, error) {
+	var version [3]int
+	if v, err := parseCudaVersionJSON(filepath.Join(root, "version.json")); err == nil {
+		return v, nil
+	}
+	if v, err := parseCudaVersionTxt(filepath.Join(root, "version.txt")); err == nil {
+		return v, nil
+	}
+	return version, fmt.Errorf("no readable version.json or version.txt under %s", root)
+}
+
+func parseCudaVersionJSON(path string) ([3]int, error) {
+	var version [3]int
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return version, err
+	}
+	var doc struct {
+		Cuda struct {
+			Version string `json:"version"`
+		} `json:"cuda"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return version, err
+	}
+	if doc.Cuda.Version == "" {
+		return version, fmt.Errorf("%s has no cuda.version field", path)
+	}
+	return splitVersion(doc.Cuda.Version)
+}
+
+func parseCudaVersionTxt(path string) ([3]int, error) {
+	var version [3]int
+	f, err := os.Open(path)
+	if err != nil {
+		return version, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := versionTxtPattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		for i := 0; i < 3; i++ {
+			version[i], _ = strconv.Atoi(m[i+1])
+		}
+		return version, nil
+	}
+	return version, fmt.Errorf("%s did not contain a \"CUDA Version X.Y.Z\" line", path)
+}
+
+func splitVersion(s string) ([3]int, error) {
+	var version [3]int
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return version, fmt.Errorf("malformed CUDA version %q", s)
+	}
+	for i := 0; i < len(parts) && i < 3; i++ {
+		v, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return version, fmt.Errorf("malformed CUDA version %q: %w", s, err)
+		}
+		version[i] = v
+	}
+	return version, nil
+}
+
+// cudaVersionLessOrEqual reports whether a <= b, comparing major, minor,
+// then patch in that order.
+func cudaVersionLessOrEqual(a, b [3]int) bool {
+	for i := 0; i < 3; i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return true
+}
+
+// cudaMajorMinorCompatible reports whether candidate's major.minor does not
+// exceed driverMax's major.minor. The patch component is deliberately
+// ignored here: nvmlSystemGetCudaDriverVersion only ever reports
+// major.minor precision, so comparing a toolkit's real patch level
+// (version.txt routinely reports e.g. 12.3.107) against a driverMax whose
+// patch is always 0 would spuriously reject toolkits the driver fully
+// supports.
+func cudaMajorMinorCompatible(candidate, driverMax [3]int) bool {
+	if candidate[0] != driverMax[0] {
+		return candidate[0] < driverMax[0]
+	}
+	return candidate[1] <= driverMax[1]
+}
+
+// ChooseCudaInstallation picks the newest toolkit install whose version
+// does not exceed the driver's reported max supported CUDA version,
+// rejecting anything newer the driver can't run. Returns nil if every
+// candidate is newer than the driver, or no candidates were found.
+func ChooseCudaInstallation(installs []CudaInstallation, driverMax [3]int) *CudaInstallation {
+	var best *CudaInstallation
+	for i := range installs {
+		candidate := installs[i]
+		if !cudaMajorMinorCompatible(candidate.Version, driverMax) {
+			slog.Debug(fmt.Sprintf("rejecting CUDA toolkit %s: newer than driver's supported %d.%d", candidate, driverMax[0], driverMax[1]))
+			continue
+		}
+		if best == nil || cudaVersionLessOrEqual(best.Version, candidate.Version) {
+			best = &candidate
+		}
+	}
+	return best
+}
@@ -25,6 +25,7 @@ import (
 type handles struct {
 	nvml   *C.nvml_handle_t
 	cudart *C.cudart_handle_t
+	cuda   *C.cuda_handle_t
 }
 
 var gpuMutex sync.Mutex
@@ -33,6 +34,12 @@ var gpuHandles *handles = nil
 // With our current CUDA compile flags, older than 5.0 will not work properly
 var CudaComputeMin = [2]C.int{5, 0}
 
+// nvmlBenignThrottleReasons are bits from nvmlDeviceGetCurrentClocksThrottleReasons
+// that reflect normal idle/clock-setting behavior rather than actual
+// throttling (thermal, power brake, etc.) - see nvmlClocksThrottleReasonGpuIdle
+// and nvmlClocksThrottleReasonApplicationsClocksSetting in gpu_info_nvml.h.
+const nvmlBenignThrottleReasons = 0x1 | 0x2
+
 // Possible locations for the nvidia-ml library
 var NvmlLinuxGlobs = []string{
 	"/usr/local/cuda/lib64/libnvidia-ml.so*",
@@ -69,6 +76,18 @@ var CudartLinuxGlobs = []string{
 	"/usr/local/lib*/libcudart.so*",
 }
 
+// Possible locations for the CUDA driver stub (libcuda.so), present even on
+// stripped-down driver installs that ship neither NVML nor cudart.
+var CudaDriverLinuxGlobs = []string{
+	"/usr/lib/wsl/lib/libcuda.so*",
+	"/usr/lib/x86_64-linux-gnu/libcuda.so*",
+	"/usr/lib/x86_64-linux-gnu/nvidia/current/libcuda.so*",
+	"/usr/lib/aarch64-linux-gnu/libcuda.so*",
+	"/usr/lib/aarch64-linux-gnu/tegra/libcuda.so*",
+	"/usr/lib*/libcuda.so*",
+	"/usr/local/cuda*/targets/*/lib/stubs/libcuda.so*",
+}
+
 // Jetson devices have JETSON_JETPACK="x.y.z" factory set to the Jetpack version installed.
 // Included to drive logic for reducing Ollama-allocated overhead on L4T/Jetson devices.
 var CudaTegra string = os.Getenv("JETSON_JETPACK")
@@ -78,11 +97,13 @@ func initGPUHandles() {
 
 	// TODO - if the ollama build is CPU only, don't do these checks as they're irrelevant and confusing
 
-	gpuHandles = &handles{nil, nil}
+	gpuHandles = &handles{nil, nil, nil}
 	var nvmlMgmtName string
 	var nvmlMgmtPatterns []string
 	var cudartMgmtName string
 	var cudartMgmtPatterns []string
+	var cudaDriverMgmtName string
+	var cudaDriverMgmtPatterns []string
 	switch runtime.GOOS {
 	case "windows":
 		nvmlMgmtName = "nvml.dll"
@@ -95,11 +116,26 @@ func initGPUHandles() {
 		cudartMgmtName = "libcudart.so"
 		cudartMgmtPatterns = make([]string, len(CudartLinuxGlobs))
 		copy(cudartMgmtPatterns, CudartLinuxGlobs)
+		cudaDriverMgmtName = "libcuda.so"
+		cudaDriverMgmtPatterns = make([]string, len(CudaDriverLinuxGlobs))
+		copy(cudaDriverMgmtPatterns, CudaDriverLinuxGlobs)
 	default:
 		return
 	}
 
 	slog.Info("Detecting GPU type")
+
+	// Discover any CUDA toolkits on the host up front, and pin the newest
+	// one the driver actually supports so both the NVML and cudart loaders
+	// below search its lib dir first instead of mixing libraries across
+	// installations.
+	cudaInstalls := DiscoverCudaInstallations()
+	for _, install := range cudaInstalls {
+		slog.Debug(fmt.Sprintf("found CUDA toolkit %s", install))
+	}
+	pinnedCuda := selectPinnedCudaInstallation(nvmlMgmtName, nvmlMgmtPatterns, cudaInstalls)
+
+	nvmlMgmtPatterns = preferCudaLibDir(nvmlMgmtPatterns, pinnedCuda, "libnvidia-ml.so")
 	nvmlLibPaths := FindGPULibs(nvmlMgmtName, nvmlMgmtPatterns)
 	if len(nvmlLibPaths) > 0 {
 		nvml := LoadNVMLMgmt(nvmlLibPaths)
@@ -110,6 +146,7 @@ func initGPUHandles() {
 		}
 	}
 
+	cudartMgmtPatterns = preferCudaLibDir(cudartMgmtPatterns, pinnedCuda, "libcudart.so")
 	cudartLibPaths := FindGPULibs(cudartMgmtName, cudartMgmtPatterns)
 	if len(cudartLibPaths) > 0 {
 		cudart := LoadCUDARTMgmt(cudartLibPaths)
@@ -120,9 +157,110 @@ func initGPUHandles() {
 		}
 	}
 
+	// Neither NVML nor cudart are installed - fall back to the driver API
+	// directly. libcuda.so.1 is present on essentially every Nvidia install,
+	// including stripped-down container, WSL, and JetPack minimal images
+	// that skip the toolkit and management library entirely.
+	if cudaDriverMgmtName != "" {
+		cudaDriverLibPaths := FindGPULibs(cudaDriverMgmtName, cudaDriverMgmtPatterns)
+		if len(cudaDriverLibPaths) > 0 {
+			cudaDriver := LoadCUDADriverMgmt(cudaDriverLibPaths)
+			if cudaDriver != nil {
+				slog.Info("Nvidia GPU detected via libcuda.so driver API")
+				gpuHandles.cuda = cudaDriver
+				return
+			}
+		}
+	}
+
+}
+
+// selectPinnedCudaInstallation probes the driver's max supported CUDA
+// version via a throwaway NVML load and uses it to gate which discovered
+// toolkit gets pinned: anything newer than the driver supports is
+// rejected, and the newest compatible one wins. Returns nil if there's
+// nothing to pin - no toolkits were found (not logged), the driver's max
+// version couldn't be determined, or no toolkit is compatible (both
+// logged) - in which case callers fall back to the generic system search
+// patterns with no preference applied.
+func selectPinnedCudaInstallation(nvmlMgmtName string, nvmlMgmtPatterns []string, installs []CudaInstallation) *CudaInstallation {
+	if len(installs) == 0 {
+		return nil
+	}
+	driverMax, err := probeDriverMaxCudaVersion(nvmlMgmtName, nvmlMgmtPatterns)
+	if err != nil {
+		slog.Debug(fmt.Sprintf("unable to determine driver's max supported CUDA version, not pinning a toolkit: %s", err))
+		return nil
+	}
+	chosen := ChooseCudaInstallation(installs, driverMax)
+	if chosen == nil {
+		slog.Warn(fmt.Sprintf("no discovered CUDA toolkit is compatible with driver's supported CUDA %d.%d", driverMax[0], driverMax[1]))
+		return nil
+	}
+	slog.Info(fmt.Sprintf("using CUDA toolkit %s (driver supports up to %d.%d)", chosen, driverMax[0], driverMax[1]))
+	return chosen
+}
+
+// probeDriverMaxCudaVersion loads NVML just long enough to ask the driver
+// what CUDA version it supports, then releases it - the real, possibly
+// differently-pinned, NVML handle used for the rest of the process
+// lifetime is loaded separately by initGPUHandles.
+func probeDriverMaxCudaVersion(nvmlMgmtName string, nvmlMgmtPatterns []string) ([3]int, error) {
+	var version [3]int
+	if nvmlMgmtName == "" {
+		return version, fmt.Errorf("no NVML library configured for this platform")
+	}
+	libPaths := FindGPULibs(nvmlMgmtName, nvmlMgmtPatterns)
+	if len(libPaths) == 0 {
+		return version, fmt.Errorf("no NVML library found to query driver CUDA version")
+	}
+	nvml := LoadNVMLMgmt(libPaths)
+	if nvml == nil {
+		return version, fmt.Errorf("unable to load NVML to query driver CUDA version")
+	}
+	defer C.nvml_release(*nvml)
+
+	var cVersion C.int
+	var cErr *C.char
+	C.nvml_get_cuda_driver_version(*nvml, &cVersion, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return version, fmt.Errorf("%s", C.GoString(cErr))
+	}
+	return [3]int{int(cVersion) / 1000, (int(cVersion) % 1000) / 10, 0}, nil
+}
+
+// preferCudaLibDir puts the lib directory of the pinned CUDA toolkit at
+// the front of the search patterns so FindGPULibs tries it before falling
+// back to the generic system globs, keeping libraries from the same
+// installation together instead of mixing versions.
+func preferCudaLibDir(patterns []string, pinned *CudaInstallation, libBaseName string) []string {
+	if pinned == nil {
+		return patterns
+	}
+	preferred := filepath.Join(pinned.LibDir, libBaseName+"*")
+	slog.Debug(fmt.Sprintf("preferring %s from pinned CUDA toolkit %s", libBaseName, pinned))
+	return append([]string{preferred}, patterns...)
 }
 
-func GetGPUInfo() GpuInfo {
+// logGPUSelectionOnce ensures the device-set/policy log line GetGPUInfo
+// emits below fires a single time per process, the way a server startup
+// log would, rather than once per poll.
+var logGPUSelectionOnce sync.Once
+
+// GetGPUInfo enumerates every GPU device visible to a supported management
+// library. Via NVML it returns one GpuInfo per device, in library
+// enumeration order; the cudart and driver API paths return a single
+// device holding the memory summed across all cards. When no GPU is
+// usable it returns a single "cpu" entry. Callers that only care about
+// aggregate capacity can sum the list with GpuInfoList.SumFreeMemory.
+func GetGPUInfo() GpuInfoList {
+	resp := getGPUInfo()
+	logGPUSelectionOnce.Do(func() { LogGPUSelection(resp) })
+	return resp
+}
+
+func getGPUInfo() GpuInfoList {
 	// TODO - consider exploring lspci (and equivalent on windows) to check for
 	// GPUs so we can report warnings if we see Nvidia/AMD but fail to load the libraries
 	gpuMutex.Lock()
@@ -138,65 +276,164 @@ func GetGPUInfo() GpuInfo {
 	}
 
 	var memInfo C.mem_info_t
-	resp := GpuInfo{}
+	resp := GpuInfoList{}
 	if gpuHandles.nvml != nil && (cpuVariant != "" || runtime.GOARCH != "amd64") {
-		C.nvml_check_vram(*gpuHandles.nvml, &memInfo)
+		resp = nvmlGetGPUInfo(*gpuHandles.nvml)
+		if len(resp) > 0 {
+			return FilterVisibleDevices(resp)
+		}
+	} else if gpuHandles.cudart != nil && (cpuVariant != "" || runtime.GOARCH != "amd64") {
+		C.cudart_check_vram(*gpuHandles.cudart, &memInfo)
 		if memInfo.err != nil {
-			slog.Info(fmt.Sprintf("[libnvidia-ml.so] error looking up NVML GPU memory: %s", C.GoString(memInfo.err)))
+			slog.Info(fmt.Sprintf("[libcudart.so] error looking up CUDART GPU memory: %s", C.GoString(memInfo.err)))
 			C.free(unsafe.Pointer(memInfo.err))
 		} else if memInfo.count > 0 {
 			// Verify minimum compute capability
-			var cc C.nvml_compute_capability_t
-			C.nvml_compute_capability(*gpuHandles.nvml, &cc)
+			var cc C.cudart_compute_capability_t
+			C.cudart_compute_capability(*gpuHandles.cudart, &cc)
 			if cc.err != nil {
-				slog.Info(fmt.Sprintf("[libnvidia-ml.so] error looking up NVML GPU compute capability: %s", C.GoString(cc.err)))
+				slog.Info(fmt.Sprintf("[libcudart.so] error looking up CUDA compute capability: %s", C.GoString(cc.err)))
 				C.free(unsafe.Pointer(cc.err))
 			} else if cc.major > CudaComputeMin[0] || (cc.major == CudaComputeMin[0] && cc.minor >= CudaComputeMin[1]) {
-				slog.Info(fmt.Sprintf("[libnvidia-ml.so] NVML CUDA Compute Capability detected: %d.%d", cc.major, cc.minor))
-				resp.Library = "cuda"
+				slog.Info(fmt.Sprintf("[libcudart.so] CUDART CUDA Compute Capability detected: %d.%d", cc.major, cc.minor))
+				// cudart doesn't expose per-device enumeration to us the way
+				// NVML does, so until that's added we report the aggregate
+				// as a single synthetic device.
+				resp = append(resp, GpuInfo{
+					Library:     "cuda",
+					ID:          "0",
+					Index:       0,
+					FreeMemory:  uint64(memInfo.free),
+					TotalMemory: uint64(memInfo.total),
+				})
+				return filterAggregateVisibility(resp)
 			} else {
-				slog.Info(fmt.Sprintf("[libnvidia-ml.so] CUDA GPU is too old. Falling back to CPU mode. Compute Capability detected: %d.%d", cc.major, cc.minor))
+				slog.Info(fmt.Sprintf("[libcudart.so] CUDA GPU is too old. Falling back to CPU mode. Compute Capability detected: %d.%d", cc.major, cc.minor))
 			}
 		}
-	} else if gpuHandles.cudart != nil && (cpuVariant != "" || runtime.GOARCH != "amd64") {
-		C.cudart_check_vram(*gpuHandles.cudart, &memInfo)
+	} else if gpuHandles.cuda != nil && (cpuVariant != "" || runtime.GOARCH != "amd64") {
+		C.cuda_check_vram(*gpuHandles.cuda, &memInfo)
 		if memInfo.err != nil {
-			slog.Info(fmt.Sprintf("[libcudart.so] error looking up CUDART GPU memory: %s", C.GoString(memInfo.err)))
+			slog.Info(fmt.Sprintf("[libcuda.so] error looking up driver API GPU memory: %s", C.GoString(memInfo.err)))
 			C.free(unsafe.Pointer(memInfo.err))
 		} else if memInfo.count > 0 {
 			// Verify minimum compute capability
-			var cc C.cudart_compute_capability_t
-			C.cudart_compute_capability(*gpuHandles.cudart, &cc)
+			var cc C.cuda_compute_capability_t
+			C.cuda_compute_capability(*gpuHandles.cuda, &cc)
 			if cc.err != nil {
-				slog.Info(fmt.Sprintf("[libcudart.so] error looking up CUDA compute capability: %s", C.GoString(cc.err)))
+				slog.Info(fmt.Sprintf("[libcuda.so] error looking up driver API compute capability: %s", C.GoString(cc.err)))
 				C.free(unsafe.Pointer(cc.err))
 			} else if cc.major > CudaComputeMin[0] || (cc.major == CudaComputeMin[0] && cc.minor >= CudaComputeMin[1]) {
-				slog.Info(fmt.Sprintf("[libcudart.so] CUDART CUDA Compute Capability detected: %d.%d", cc.major, cc.minor))
-				resp.Library = "cuda"
+				slog.Info(fmt.Sprintf("[libcuda.so] driver API CUDA Compute Capability detected: %d.%d", cc.major, cc.minor))
+				// Like cudart, the driver API path doesn't give us a nicely
+				// broken out per-device list yet, so it's reported as a
+				// single synthetic device until that's worth the trouble.
+				resp = append(resp, GpuInfo{
+					Library:     "cuda",
+					ID:          "0",
+					Index:       0,
+					FreeMemory:  uint64(memInfo.free),
+					TotalMemory: uint64(memInfo.total),
+				})
+				return filterAggregateVisibility(resp)
 			} else {
-				slog.Info(fmt.Sprintf("[libcudart.so] CUDA GPU is too old. Falling back to CPU mode. Compute Capability detected: %d.%d", cc.major, cc.minor))
+				slog.Info(fmt.Sprintf("[libcuda.so] CUDA GPU is too old. Falling back to CPU mode. Compute Capability detected: %d.%d", cc.major, cc.minor))
 			}
 		}
 	} else {
-		AMDGetGPUInfo(&resp)
-		if resp.Library != "" {
-			return resp
+		var amd GpuInfo
+		AMDGetGPUInfo(&amd)
+		if amd.Library != "" {
+			return GpuInfoList{amd}
 		}
 	}
-	if resp.Library == "" {
-		C.cpu_check_ram(&memInfo)
-		resp.Library = "cpu"
-		resp.Variant = cpuVariant
-	}
+
+	C.cpu_check_ram(&memInfo)
 	if memInfo.err != nil {
 		slog.Info(fmt.Sprintf("error looking up CPU memory: %s", C.GoString(memInfo.err)))
 		C.free(unsafe.Pointer(memInfo.err))
+		return GpuInfoList{{Library: "cpu", Variant: cpuVariant}}
+	}
+	return GpuInfoList{{
+		Library:     "cpu",
+		Variant:     cpuVariant,
+		FreeMemory:  uint64(memInfo.free),
+		TotalMemory: uint64(memInfo.total),
+	}}
+}
+
+// nvmlGetGPUInfo walks every device NVML reports and gathers full telemetry
+// for each one. An empty list is returned (with diagnostics logged) if NVML
+// can't enumerate any devices or none meet CudaComputeMin.
+func nvmlGetGPUInfo(h C.nvml_handle_t) GpuInfoList {
+	resp := GpuInfoList{}
+
+	var count C.uint
+	var cErr *C.char
+	C.nvml_get_device_count(h, &count, &cErr)
+	if cErr != nil {
+		slog.Info(fmt.Sprintf("[libnvidia-ml.so] error enumerating NVML devices: %s", C.GoString(cErr)))
+		C.free(unsafe.Pointer(cErr))
 		return resp
 	}
 
-	resp.DeviceCount = uint32(memInfo.count)
-	resp.FreeMemory = uint64(memInfo.free)
-	resp.TotalMemory = uint64(memInfo.total)
+	for i := 0; i < int(count); i++ {
+		var t C.gpu_telemetry_t
+		C.nvml_get_device_telemetry(h, C.uint(i), &t)
+		if t.err != nil {
+			slog.Info(fmt.Sprintf("[libnvidia-ml.so] error reading telemetry for device %d: %s", i, C.GoString(t.err)))
+			C.free(unsafe.Pointer(t.err))
+			continue
+		}
+
+		if t.major > CudaComputeMin[0] || (t.major == CudaComputeMin[0] && t.minor >= CudaComputeMin[1]) {
+			slog.Info(fmt.Sprintf("[libnvidia-ml.so] NVML device %d Compute Capability detected: %d.%d", i, t.major, t.minor))
+		} else {
+			slog.Info(fmt.Sprintf("[libnvidia-ml.so] device %d is too old, skipping. Compute Capability detected: %d.%d", i, t.major, t.minor))
+			continue
+		}
+
+		id := C.GoString(&t.uuid[0])
+		if id == "" {
+			id = strconv.Itoa(i)
+		}
+
+		// nvmlClocksThrottleReasonGpuIdle/ApplicationsClocksSetting are set
+		// on perfectly healthy GPUs that are simply idle or clocked down on
+		// purpose - mask them out so idle cards don't get reported as
+		// throttled.
+		throttling := uint64(t.throttle_reasons) &^ nvmlBenignThrottleReasons
+
+		var clocks *ClockInfo
+		if t.clock_sm_mhz != 0 || t.clock_memory_mhz != 0 || t.clock_graphics_mhz != 0 {
+			clocks = &ClockInfo{
+				SM:       uint32(t.clock_sm_mhz),
+				Memory:   uint32(t.clock_memory_mhz),
+				Graphics: uint32(t.clock_graphics_mhz),
+			}
+		}
+
+		resp = append(resp, GpuInfo{
+			Library:     "cuda",
+			ID:          id,
+			Name:        C.GoString(&t.gpu_name[0]),
+			Index:       i,
+			FreeMemory:  uint64(t.free),
+			TotalMemory: uint64(t.total),
+			Compute:     fmt.Sprintf("%d.%d", t.major, t.minor),
+
+			TemperatureC:          uint32(t.temperature_c),
+			PowerUsageWatts:       float64(t.power_usage_milliwatts) / 1000.0,
+			PowerLimitWatts:       float64(t.power_limit_milliwatts) / 1000.0,
+			Clocks:                clocks,
+			UtilizationGPUPercent: uint32(t.utilization_gpu_percent),
+			UtilizationMemPercent: uint32(t.utilization_memory_percent),
+			FanSpeedPercent:       uint32(t.fan_speed_percent),
+			Throttled:             throttling != 0,
+			ThrottleReason:        C.GoString(&t.throttle_desc[0]),
+		})
+	}
+
 	return resp
 }
 
@@ -213,35 +450,77 @@ func getCPUMem() (memInfo, error) {
 	return ret, nil
 }
 
-func CheckVRAM() (int64, error) {
-	userLimit := os.Getenv("OLLAMA_MAX_VRAM")
-	if userLimit != "" {
-		avail, err := strconv.ParseInt(userLimit, 10, 64)
-		if err != nil {
-			return 0, fmt.Errorf("Invalid OLLAMA_MAX_VRAM setting %s: %s", userLimit, err)
+// DeviceAvailableMemory is a single device's free memory after reserving
+// a per-device overhead.
+type DeviceAvailableMemory struct {
+	GpuInfo
+	Available int64
+}
+
+// CheckDeviceVRAM returns the available memory for each GPU that
+// DevicesForPlacement selects (only one under GpuSelectionSingle),
+// individually and in placement order.
+// Placement decisions - does a given model fit on *this* card - should
+// range over this rather than a rig-wide sum: on a mixed rig (e.g. one
+// 24GB + one 8GB card) the sum can easily be large enough for a model
+// that doesn't actually fit on either device alone. CheckVRAM wraps this
+// with the OLLAMA_MAX_VRAM override.
+func CheckDeviceVRAM() ([]DeviceAvailableMemory, error) {
+	gpuInfo := DevicesForPlacement()
+	resp := make([]DeviceAvailableMemory, 0, len(gpuInfo))
+	for _, info := range gpuInfo {
+		if info.Library != "cuda" && info.Library != "rocm" {
+			continue
 		}
-		slog.Info(fmt.Sprintf("user override OLLAMA_MAX_VRAM=%d", avail))
-		return avail, nil
-	}
-	gpuInfo := GetGPUInfo()
-	if gpuInfo.FreeMemory > 0 && (gpuInfo.Library == "cuda" || gpuInfo.Library == "rocm") {
 		// leave 10% or 1024MiB of VRAM free per GPU to handle unaccounted for overhead
-		overhead := gpuInfo.FreeMemory / 10
-		gpus := uint64(gpuInfo.DeviceCount)
-		if overhead < gpus*1024*1024*1024 {
-			overhead = gpus * 1024 * 1024 * 1024
+		overhead := info.FreeMemory / 10
+		if overhead < 1024*1024*1024 {
+			overhead = 1024 * 1024 * 1024
 		}
 		// Assigning full reported free memory for Tegras due to OS controlled caching.
 		if CudaTegra != "" {
-			// Setting overhead for non-Tegra devices
 			overhead = 0
 		}
-		avail := int64(gpuInfo.FreeMemory - overhead)
-		slog.Debug(fmt.Sprintf("%s detected %d devices with %dM available memory", gpuInfo.Library, gpuInfo.DeviceCount, avail/1024/1024))
-		return avail, nil
+		avail := int64(info.FreeMemory) - int64(overhead)
+		if avail < 0 {
+			avail = 0
+		}
+		resp = append(resp, DeviceAvailableMemory{GpuInfo: info, Available: avail})
 	}
+	if len(resp) == 0 {
+		return nil, fmt.Errorf("no GPU detected")
+	}
+	return resp, nil
+}
 
-	return 0, fmt.Errorf("no GPU detected") // TODO - better handling of CPU based memory determiniation
+// CheckVRAM is the entry point placement decisions should range over: it
+// reports available memory per device instead of summing across the whole
+// rig, so a model that doesn't fit on any single card is never mistaken
+// for one that fits because two smaller cards add up to enough. Callers
+// that only want a rig-wide capacity number (e.g. log lines) can still sum
+// the result themselves.
+func CheckVRAM() ([]DeviceAvailableMemory, error) {
+	userLimit := os.Getenv("OLLAMA_MAX_VRAM")
+	if userLimit != "" {
+		avail, err := strconv.ParseInt(userLimit, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid OLLAMA_MAX_VRAM setting %s: %s", userLimit, err)
+		}
+		slog.Info(fmt.Sprintf("user override OLLAMA_MAX_VRAM=%d", avail))
+		return []DeviceAvailableMemory{{Available: avail}}, nil
+	}
+	devices, err := CheckDeviceVRAM()
+	if err != nil {
+		return nil, err // TODO - better handling of CPU based memory determiniation
+	}
+	var total int64
+	var library string
+	for _, d := range devices {
+		total += d.Available
+		library = d.Library
+	}
+	slog.Debug(fmt.Sprintf("%s detected %d devices with %dM available memory", library, len(devices), total/1024/1024))
+	return devices, nil
 }
 
 func FindGPULibs(baseLibName string, patterns []string) []string {
@@ -332,6 +611,23 @@ func LoadCUDARTMgmt(cudartLibPaths []string) *C.cudart_handle_t {
 	return nil
 }
 
+func LoadCUDADriverMgmt(cudaLibPaths []string) *C.cuda_handle_t {
+	var resp C.cuda_init_resp_t
+	resp.ch.verbose = getVerboseState()
+	for _, libPath := range cudaLibPaths {
+		lib := C.CString(libPath)
+		defer C.free(unsafe.Pointer(lib))
+		C.cuda_init(lib, &resp)
+		if resp.err != nil {
+			slog.Info(fmt.Sprintf("Unable to load CUDA driver library %s: %s", libPath, C.GoString(resp.err)))
+			C.free(unsafe.Pointer(resp.err))
+		} else {
+			return &resp.ch
+		}
+	}
+	return nil
+}
+
 func getVerboseState() C.uint16_t {
 	if debug := os.Getenv("OLLAMA_DEBUG"); debug != "" {
 		return C.uint16_t(1)
@@ -0,0 +1,17 @@
+package gpu
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ServeGPUInfo writes the current per-device GPU telemetry (temperature,
+// power, clocks, utilization, throttle state) as JSON. The server package
+// mounts this at GET /api/gpu so operators can monitor rack health while
+// models are loaded.
+func ServeGPUInfo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(GetGPUInfo()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,94 @@
+package gpu
+
+import "fmt"
+
+// ClockInfo reports the current clock speeds NVML exposes for a device, in
+// MHz. Any value that the driver didn't support reporting is left at 0.
+type ClockInfo struct {
+	SM       uint32 `json:"sm_mhz"`
+	Memory   uint32 `json:"memory_mhz"`
+	Graphics uint32 `json:"graphics_mhz"`
+}
+
+// GpuInfo describes a single GPU device available to Ollama, along with
+// whatever telemetry the management library backing it was able to report.
+type GpuInfo struct {
+	Library string `json:"library,omitempty"`
+	Variant string `json:"variant,omitempty"`
+
+	// ID is a stable identifier for this device: the vendor UUID when one
+	// is available, otherwise the enumeration ordinal.
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+
+	// Index is the ordinal reported by the management library, except when
+	// CUDA_VISIBLE_DEVICES/NVIDIA_VISIBLE_DEVICES restricts the list: then
+	// FilterVisibleDevices renumbers the kept devices 0..N-1. It is not
+	// stable across reboots or driver upgrades - prefer ID for that.
+	Index int `json:"index"`
+
+	FreeMemory  uint64 `json:"free_memory"`
+	TotalMemory uint64 `json:"total_memory"`
+
+	Compute string `json:"compute,omitempty"` // e.g. "8.6"
+
+	TemperatureC    uint32  `json:"temperature_c,omitempty"`
+	PowerUsageWatts float64 `json:"power_usage_watts,omitempty"`
+	PowerLimitWatts float64 `json:"power_limit_watts,omitempty"`
+	// Clocks is nil when the management library couldn't report any clock
+	// speeds for this device, rather than a struct of all zeroes - a plain
+	// ClockInfo value would always serialize since omitempty is a no-op on
+	// structs.
+	Clocks                *ClockInfo `json:"clocks,omitempty"`
+	UtilizationGPUPercent uint32     `json:"utilization_gpu_percent,omitempty"`
+	UtilizationMemPercent uint32     `json:"utilization_memory_percent,omitempty"`
+	FanSpeedPercent       uint32     `json:"fan_speed_percent,omitempty"`
+	Throttled             bool       `json:"throttled,omitempty"`
+	ThrottleReason        string     `json:"throttle_reason,omitempty"`
+}
+
+// GpuInfoList is the full set of devices discovered on this host, in
+// enumeration order.
+type GpuInfoList []GpuInfo
+
+// ByLibrary splits the list into groups that share the same backing
+// library, mirroring how llama.cpp needs to be told which runtime to use.
+func (l GpuInfoList) ByLibrary() []GpuInfoList {
+	resp := []GpuInfoList{}
+	libs := []string{}
+	for _, info := range l {
+		found := false
+		for i, lib := range libs {
+			if lib == info.Library {
+				resp[i] = append(resp[i], info)
+				found = true
+				break
+			}
+		}
+		if !found {
+			libs = append(libs, info.Library)
+			resp = append(resp, GpuInfoList{info})
+		}
+	}
+	return resp
+}
+
+// SumFreeMemory returns the total free VRAM across every device in the
+// list. Useful for call sites that haven't yet been updated to make
+// per-device placement decisions.
+func (l GpuInfoList) SumFreeMemory() uint64 {
+	var total uint64
+	for _, info := range l {
+		total += info.FreeMemory
+	}
+	return total
+}
+
+// DeviceCount returns the number of devices in the list.
+func (l GpuInfoList) DeviceCount() int {
+	return len(l)
+}
+
+func (g GpuInfo) String() string {
+	return fmt.Sprintf("%s device %d (%s) free=%d total=%d", g.Library, g.Index, g.ID, g.FreeMemory, g.TotalMemory)
+}
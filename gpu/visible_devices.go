@@ -0,0 +1,233 @@
+package gpu
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GpuSelectionPolicy controls how the model-loading layer spreads work
+// across multiple visible GPUs. Set via OLLAMA_GPU_SELECTION.
+type GpuSelectionPolicy string
+
+const (
+	// GpuSelectionFill packs each GPU as full as possible before moving on
+	// to the next one. This is the default - it matches today's behavior
+	// of treating free memory as one pool, just applied device by device.
+	GpuSelectionFill GpuSelectionPolicy = "fill"
+	// GpuSelectionSpread distributes load evenly across every visible
+	// device instead of maximizing any single one.
+	GpuSelectionSpread GpuSelectionPolicy = "spread"
+	// GpuSelectionLargestFirst prefers the device with the most free
+	// memory, which matters most on a mixed-VRAM rig.
+	GpuSelectionLargestFirst GpuSelectionPolicy = "largest-first"
+	// GpuSelectionSingle restricts placement to exactly one device - the
+	// first one in the (already filtered) visible list.
+	GpuSelectionSingle GpuSelectionPolicy = "single"
+)
+
+// GetGPUSelectionPolicy returns the policy requested via
+// OLLAMA_GPU_SELECTION, defaulting to GpuSelectionFill for anything unset
+// or unrecognized.
+func GetGPUSelectionPolicy() GpuSelectionPolicy {
+	switch GpuSelectionPolicy(strings.ToLower(os.Getenv("OLLAMA_GPU_SELECTION"))) {
+	case GpuSelectionSpread:
+		return GpuSelectionSpread
+	case GpuSelectionLargestFirst:
+		return GpuSelectionLargestFirst
+	case GpuSelectionSingle:
+		return GpuSelectionSingle
+	default:
+		return GpuSelectionFill
+	}
+}
+
+// OrderForPlacement returns the devices in the list, reordered according
+// to policy. It never drops devices - callers that only want one (e.g.
+// GpuSelectionSingle) should take list[0] of the result themselves, or use
+// DevicesForPlacement which does that for them.
+func (l GpuInfoList) OrderForPlacement(policy GpuSelectionPolicy) GpuInfoList {
+	ordered := make(GpuInfoList, len(l))
+	copy(ordered, l)
+	switch policy {
+	case GpuSelectionLargestFirst:
+		sortByFreeMemoryDesc(ordered)
+	case GpuSelectionSpread, GpuSelectionFill, GpuSelectionSingle:
+		// Fill and spread both want devices in their natural enumeration
+		// order - fill packs them front to back, spread round-robins
+		// across them - the difference is in how the caller consumes the
+		// order, not the order itself. Single also keeps natural order: it
+		// means "use the first visible device", not "use the biggest one" -
+		// that's largest-first's job.
+	}
+	return ordered
+}
+
+// DevicesForPlacement returns the visible devices ordered according to
+// OLLAMA_GPU_SELECTION, limited to a single device for GpuSelectionSingle.
+// This is what the model-loading layer should range over when deciding
+// where to place a model. GetGPUInfo itself stays a plain, unordered
+// enumeration so monitoring callers like the /api/gpu endpoint always see
+// every device.
+func DevicesForPlacement() GpuInfoList {
+	policy := GetGPUSelectionPolicy()
+	devices := GetGPUInfo().OrderForPlacement(policy)
+	if policy == GpuSelectionSingle && len(devices) > 1 {
+		devices = devices[:1]
+	}
+	return devices
+}
+
+func sortByFreeMemoryDesc(l GpuInfoList) {
+	for i := 1; i < len(l); i++ {
+		for j := i; j > 0 && l[j].FreeMemory > l[j-1].FreeMemory; j-- {
+			l[j], l[j-1] = l[j-1], l[j]
+		}
+	}
+}
+
+// visibleDeviceSelector is the parsed form of CUDA_VISIBLE_DEVICES /
+// NVIDIA_VISIBLE_DEVICES: either every device, none, or an explicit
+// allowlist of ordinals and/or "GPU-<uuid>" entries.
+type visibleDeviceSelector struct {
+	all     bool
+	none    bool
+	indices map[int]bool
+	uuids   map[string]bool
+}
+
+// parseVisibleDevices reads CUDA_VISIBLE_DEVICES first since it's the more
+// specific of the two and the one the CUDA runtime itself honors; falls
+// back to NVIDIA_VISIBLE_DEVICES, which is what the container runtime
+// (nvidia-container-toolkit) sets. Neither being set means "all".
+//
+// Numeric entries are matched against the NVML/PCI-bus enumeration order
+// (see GpuInfo.Index), not CUDA's own default FASTEST_FIRST device order -
+// this only lines up with what the CUDA runtime itself would pick when
+// CUDA_DEVICE_ORDER=PCI_BUS_ID is set, which is standard practice for
+// multi-GPU hosts but not a given. "GPU-<uuid>" / "MIG-<uuid>" entries
+// match on prefix, matching nvidia-container-toolkit and the CUDA runtime,
+// which both accept a unique leading substring of the full UUID.
+func parseVisibleDevices() visibleDeviceSelector {
+	spec := os.Getenv("CUDA_VISIBLE_DEVICES")
+	if spec == "" {
+		spec = os.Getenv("NVIDIA_VISIBLE_DEVICES")
+	}
+	if spec == "" {
+		return visibleDeviceSelector{all: true}
+	}
+
+	switch strings.ToLower(spec) {
+	case "all":
+		return visibleDeviceSelector{all: true}
+	case "none", "void":
+		return visibleDeviceSelector{none: true}
+	}
+
+	sel := visibleDeviceSelector{indices: map[int]bool{}, uuids: map[string]bool{}}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.HasPrefix(entry, "GPU-") || strings.HasPrefix(entry, "MIG-") {
+			sel.uuids[strings.ToLower(entry)] = true
+			continue
+		}
+		idx, err := strconv.Atoi(entry)
+		if err != nil {
+			slog.Warn("ignoring unrecognized CUDA_VISIBLE_DEVICES/NVIDIA_VISIBLE_DEVICES entry", "entry", entry)
+			continue
+		}
+		sel.indices[idx] = true
+	}
+	return sel
+}
+
+func (s visibleDeviceSelector) matches(info GpuInfo) bool {
+	if s.all {
+		return true
+	}
+	if s.none {
+		return false
+	}
+	if s.indices[info.Index] {
+		return true
+	}
+	id := strings.ToLower(info.ID)
+	for uuid := range s.uuids {
+		// CUDA_VISIBLE_DEVICES/NVIDIA_VISIBLE_DEVICES only require a
+		// unique leading substring of the full UUID, not an exact match.
+		if strings.HasPrefix(id, uuid) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogGPUSelection records the final, filtered device set and the active
+// placement policy. GetGPUInfo calls it once per process, on its first
+// call, so operators can see exactly which cards Ollama will place work on.
+func LogGPUSelection(list GpuInfoList) {
+	policy := GetGPUSelectionPolicy()
+	ids := make([]string, 0, len(list))
+	for _, info := range list {
+		ids = append(ids, info.String())
+	}
+	slog.Info("GPU device selection", "policy", policy, "devices", ids)
+}
+
+// warnAggregateVisibilityOnce limits the filterAggregateVisibility warning
+// below to a single log line per process instead of one per poll.
+var warnAggregateVisibilityOnce sync.Once
+
+// filterAggregateVisibility handles CUDA_VISIBLE_DEVICES/NVIDIA_VISIBLE_DEVICES
+// for the cudart and CUDA-driver-API paths, which report a single synthetic
+// device whose memory is already summed across every physical card.
+// FilterVisibleDevices can't meaningfully restrict *which* card is used on
+// an aggregate like that - matching it against an index or UUID selector
+// either keeps the whole aggregate or drops it entirely, and the latter
+// would silently report "no GPU" even though one is visible. Warn instead
+// of pretending to filter, and fall back to NVML (which does enumerate
+// per-device) if restricting to specific cards matters.
+func filterAggregateVisibility(list GpuInfoList) GpuInfoList {
+	sel := parseVisibleDevices()
+	if sel.all {
+		return list
+	}
+	if sel.none {
+		return GpuInfoList{}
+	}
+	warnAggregateVisibilityOnce.Do(func() {
+		slog.Warn("CUDA_VISIBLE_DEVICES/NVIDIA_VISIBLE_DEVICES is set, but this GPU library only reports an aggregate device and can't restrict which physical card is used - install NVML for per-device visibility filtering")
+	})
+	return list
+}
+
+// FilterVisibleDevices applies CUDA_VISIBLE_DEVICES/NVIDIA_VISIBLE_DEVICES
+// to a device list and remaps Index so downstream code always sees a dense
+// 0..N-1 ordinal space regardless of which physical devices were excluded.
+func FilterVisibleDevices(list GpuInfoList) GpuInfoList {
+	if len(list) == 0 {
+		return list
+	}
+	sel := parseVisibleDevices()
+	if sel.all {
+		return list
+	}
+
+	filtered := GpuInfoList{}
+	for _, info := range list {
+		if sel.matches(info) {
+			info.Index = len(filtered)
+			filtered = append(filtered, info)
+		}
+	}
+	if len(filtered) < len(list) {
+		slog.Info("GPU visibility restricted by CUDA_VISIBLE_DEVICES/NVIDIA_VISIBLE_DEVICES",
+			"visible", len(filtered), "total", len(list))
+	}
+	return filtered
+}